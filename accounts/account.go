@@ -0,0 +1,47 @@
+// Package accounts aggregates calendar data across multiple Google
+// accounts, fetching each one concurrently and caching calendar and
+// event lookups to keep the tool's API usage well inside the free
+// quota even when run frequently.
+package accounts
+
+import (
+	"context"
+
+	calendar "google.golang.org/api/calendar/v3"
+
+	"github.com/lootek/gomobile-gcal-summary/calclient"
+)
+
+// Account is a single authenticated Google account, identified by ID
+// (used as a cache key and in error messages, not by Google itself).
+type Account struct {
+	ID string
+
+	cc *calclient.CalClient
+}
+
+// NewAccount returns an Account wrapping cc, identified by id.
+func NewAccount(id string, cc *calclient.CalClient) *Account {
+	return &Account{ID: id, cc: cc}
+}
+
+// Authenticate authenticates the account's underlying CalClient.
+func (a *Account) Authenticate(ctx context.Context) error {
+	return a.cc.Authenticate(ctx)
+}
+
+// calendarList returns the account's calendar list.
+func (a *Account) calendarList() (*calendar.CalendarList, error) {
+	return a.cc.Service().CalendarList.List().ShowHidden(false).Do()
+}
+
+// events returns the events of calendarID within [timeMin, timeMax].
+func (a *Account) events(calendarID, timeMin, timeMax string) (*calendar.Events, error) {
+	return a.cc.Service().Events.List(calendarID).
+		ShowDeleted(false).
+		SingleEvents(true).
+		TimeMin(timeMin).
+		TimeMax(timeMax).
+		OrderBy("startTime").
+		Do()
+}
@@ -0,0 +1,134 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	calendar "google.golang.org/api/calendar/v3"
+
+	"github.com/lootek/gomobile-gcal-summary/cache"
+	"github.com/lootek/gomobile-gcal-summary/timetracker"
+)
+
+const (
+	// calendarListTTL bounds how stale a cached CalendarList may be
+	// before it's re-fetched. Calendars are added/removed rarely, so a
+	// long TTL is fine.
+	calendarListTTL = 6 * time.Hour
+	// eventsTTL bounds how stale a cached page of events may be.
+	// Shorter, since events change throughout the day.
+	eventsTTL = 15 * time.Minute
+)
+
+// Aggregator fetches events across a set of accounts concurrently,
+// caching both calendar lists and event pages to cut down on API
+// calls.
+type Aggregator struct {
+	accounts []*Account
+	cache    *cache.Store
+}
+
+// NewAggregator returns an Aggregator over accounts, caching responses
+// in store.
+func NewAggregator(accounts []*Account, store *cache.Store) *Aggregator {
+	return &Aggregator{accounts: accounts, cache: store}
+}
+
+// FetchEvents concurrently fetches every account's matching events in
+// [timeMin, timeMax] and returns them combined.
+func (a *Aggregator) FetchEvents(ctx context.Context, timeMin, timeMax time.Time) ([]timetracker.Event, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	perAccount := make([][]timetracker.Event, len(a.accounts))
+
+	for i, account := range a.accounts {
+		i, account := i, account
+
+		g.Go(func() error {
+			events, err := a.fetchAccount(account, timeMin, timeMax)
+			if err != nil {
+				return fmt.Errorf("accounts: %s: %w", account.ID, err)
+			}
+
+			perAccount[i] = events
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var events []timetracker.Event
+	for _, e := range perAccount {
+		events = append(events, e...)
+	}
+
+	return events, nil
+}
+
+func (a *Aggregator) fetchAccount(account *Account, timeMin, timeMax time.Time) ([]timetracker.Event, error) {
+	list, err := a.calendarList(account)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve calendar list: %w", err)
+	}
+
+	var events []timetracker.Event
+
+	for _, cal := range list.Items {
+		result, err := a.events(account, cal.Id, timeMin, timeMax)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve events for calendar %q: %w", cal.Id, err)
+		}
+
+		for _, ev := range result.Items {
+			events = append(events, timetracker.Event{CalendarID: cal.Id, Event: ev})
+		}
+	}
+
+	return events, nil
+}
+
+func (a *Aggregator) calendarList(account *Account) (*calendar.CalendarList, error) {
+	key := cache.Key(account.ID, "calendar-list")
+
+	if list, ok := cache.Get[*calendar.CalendarList](a.cache, key, calendarListTTL); ok {
+		return list, nil
+	}
+
+	list, err := account.calendarList()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cache.Set(a.cache, key, list); err != nil {
+		return nil, fmt.Errorf("unable to cache calendar list: %w", err)
+	}
+
+	return list, nil
+}
+
+func (a *Aggregator) events(account *Account, calendarID string, timeMin, timeMax time.Time) (*calendar.Events, error) {
+	timeMinStr := timeMin.Format(time.RFC3339)
+	timeMaxStr := timeMax.Format(time.RFC3339)
+
+	key := cache.Key(account.ID, calendarID, timeMinStr, timeMaxStr)
+
+	if events, ok := cache.Get[*calendar.Events](a.cache, key, eventsTTL); ok {
+		return events, nil
+	}
+
+	events, err := account.events(calendarID, timeMinStr, timeMaxStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cache.Set(a.cache, key, events); err != nil {
+		return nil, fmt.Errorf("unable to cache events: %w", err)
+	}
+
+	return events, nil
+}
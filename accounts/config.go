@@ -0,0 +1,59 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lootek/gomobile-gcal-summary/calclient"
+)
+
+// AccountConfig describes one Google account to aggregate: where to
+// find its OAuth client secret and where to cache its token.
+type AccountConfig struct {
+	ID         string `json:"id"`
+	SecretPath string `json:"secret_path"`
+	TokenPath  string `json:"token_path"`
+}
+
+// Config is a list of accounts to aggregate, as loaded from a JSON
+// config file.
+type Config struct {
+	Accounts []AccountConfig `json:"accounts"`
+}
+
+// LoadConfig reads a Config from a JSON file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: unable to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("accounts: unable to parse config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Load builds an Account for each entry in c.Accounts.
+func (c *Config) Load() ([]*Account, error) {
+	result := make([]*Account, 0, len(c.Accounts))
+
+	for _, ac := range c.Accounts {
+		secret, err := os.ReadFile(ac.SecretPath)
+		if err != nil {
+			return nil, fmt.Errorf("accounts: unable to read secret for %q: %w", ac.ID, err)
+		}
+
+		cc, err := calclient.New(secret, calclient.NewFileTokenStore(ac.TokenPath))
+		if err != nil {
+			return nil, fmt.Errorf("accounts: unable to create client for %q: %w", ac.ID, err)
+		}
+
+		result = append(result, NewAccount(ac.ID, cc))
+	}
+
+	return result, nil
+}
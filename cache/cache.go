@@ -0,0 +1,91 @@
+// Package cache is a small TTL cache for API responses, backed by a
+// JSON file per entry under a directory on disk. It exists so a tool
+// polling the Calendar API frequently (e.g. from a mobile widget)
+// doesn't blow through the API's daily quota re-fetching calendar
+// lists and event pages that haven't changed.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Key joins parts into a single cache key, e.g. Key(accountID,
+// calendarID, timeMin, timeMax).
+func Key(parts ...string) string {
+	return strings.Join(parts, "\x1f")
+}
+
+// Store is a directory of cache entries, each a JSON file named after
+// the hash of its key.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a Store backed by files under dir, creating dir if
+// it doesn't exist.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+func (s *Store) pathFor(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get looks up key and, if present and younger than ttl, decodes its
+// payload into a value of type T.
+func Get[T any](s *Store, key string, ttl time.Duration) (T, bool) {
+	var zero T
+
+	data, err := os.ReadFile(s.pathFor(key))
+	if err != nil {
+		return zero, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return zero, false
+	}
+
+	if time.Since(e.StoredAt) > ttl {
+		return zero, false
+	}
+
+	var value T
+	if err := json.Unmarshal(e.Payload, &value); err != nil {
+		return zero, false
+	}
+
+	return value, true
+}
+
+// Set stores value under key, timestamped with the current time.
+func Set[T any](s *Store, key string, value T) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry{StoredAt: time.Now(), Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.pathFor(key), data, 0600)
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetSetRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	type payload struct{ N int }
+
+	if err := Set(store, "k", payload{N: 42}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, ok := Get[payload](store, "k", time.Hour)
+	if !ok {
+		t.Fatal("Get: not found")
+	}
+
+	if got.N != 42 {
+		t.Errorf("got.N = %d, want 42", got.N)
+	}
+}
+
+func TestGetMissingKey(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	if _, ok := Get[string](store, "missing", time.Hour); ok {
+		t.Error("Get on missing key returned ok=true")
+	}
+}
+
+func TestGetExpiredEntry(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	if err := Set(store, "k", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// Back-date the stored entry so it reads as older than the TTL,
+	// without depending on a real sleep.
+	entryPath := store.pathFor("k")
+
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	e.StoredAt = e.StoredAt.Add(-2 * time.Hour)
+
+	data, err = json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if err := os.WriteFile(entryPath, data, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, ok := Get[string](store, "k", time.Hour); ok {
+		t.Error("Get on expired entry returned ok=true")
+	}
+}
+
+func TestKeyJoinsPartsDistinctly(t *testing.T) {
+	a := Key("acct1", "cal1")
+	b := Key("acct1cal1")
+
+	if a == b {
+		t.Error("Key did not distinguish differently-split parts")
+	}
+}
+
+func TestNewStoreCreatesDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "cache")
+
+	if _, err := NewStore(dir); err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("cache dir not created: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Package calclient wraps the Google Calendar API behind a small client
+// type that doesn't assume a filesystem, a terminal, or any particular
+// token storage. It exists so the same code can back a CLI and be built
+// into a gomobile library, where "read client_secret.json from CWD" and
+// "paste a code into a terminal" aren't options.
+package calclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// TokenStore persists and retrieves an OAuth2 token for a CalClient.
+// Implementations might write to disk, a keyring, or just hold the token
+// in memory; CalClient only needs Load/Save.
+type TokenStore interface {
+	// Load returns the previously saved token. It returns an error if no
+	// token has been saved yet.
+	Load() (*oauth2.Token, error)
+	// Save persists token for later retrieval by Load.
+	Save(token *oauth2.Token) error
+}
+
+// CalClient is a Google Calendar client authenticated via OAuth2. It is
+// constructed from the raw bytes of a Google OAuth client secret rather
+// than a file path, so callers can embed the secret, load it from an
+// app bundle, or fetch it from anywhere else.
+type CalClient struct {
+	config *oauth2.Config
+	store  TokenStore
+
+	httpClient *http.Client
+	srv        *calendar.Service
+}
+
+// New parses secretJSON (the contents of a Google "client_secret.json")
+// and returns a CalClient that will use store to load and save the
+// resulting OAuth2 token. Authenticate must be called before Service.
+func New(secretJSON []byte, store TokenStore) (*CalClient, error) {
+	config, err := google.ConfigFromJSON(secretJSON, calendar.CalendarReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("calclient: unable to parse client secret: %w", err)
+	}
+
+	return &CalClient{config: config, store: store}, nil
+}
+
+// Authenticate loads a token from the client's TokenStore, obtaining a
+// new one via getTokenFromWeb if none is stored yet, and uses it to
+// build the underlying calendar.Service. getTokenFromWeb assumes a
+// terminal or a launchable browser, neither of which exists in a
+// gomobile build; embedders that drive their own OAuth UI should use
+// AuthCodeURL and ExchangeCode instead of calling Authenticate.
+func (c *CalClient) Authenticate(ctx context.Context) error {
+	tok, err := c.store.Load()
+	if err != nil {
+		tok, err = getTokenFromWeb(ctx, c.config)
+		if err != nil {
+			return fmt.Errorf("calclient: unable to retrieve token from web: %w", err)
+		}
+
+		if err := c.store.Save(tok); err != nil {
+			return fmt.Errorf("calclient: unable to save token: %w", err)
+		}
+	}
+
+	return c.buildService(ctx, tok)
+}
+
+// NewState returns a random opaque string suitable for passing as
+// state to AuthCodeURL and for checking against the state an
+// embedder's OAuth UI reports back.
+func NewState() (string, error) {
+	return randomState()
+}
+
+// AuthCodeURL returns the consent page URL for state, which the caller
+// is responsible for generating (e.g. a random token) and later
+// checking against whatever its OAuth UI reports back. It lets an
+// embedder that can't exec a browser or read stdin - such as a
+// gomobile host app - drive the OAuth flow with its own UI instead of
+// going through Authenticate.
+func (c *CalClient) AuthCodeURL(state string) string {
+	return c.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+}
+
+// ExchangeCode exchanges an authorization code obtained via a URL from
+// AuthCodeURL for a token, saves it to the TokenStore, and builds the
+// underlying calendar.Service so Service can be called afterward.
+func (c *CalClient) ExchangeCode(ctx context.Context, code string) error {
+	tok, err := c.config.Exchange(ctx, code)
+	if err != nil {
+		return fmt.Errorf("calclient: unable to exchange authorization code: %w", err)
+	}
+
+	if err := c.store.Save(tok); err != nil {
+		return fmt.Errorf("calclient: unable to save token: %w", err)
+	}
+
+	return c.buildService(ctx, tok)
+}
+
+func (c *CalClient) buildService(ctx context.Context, tok *oauth2.Token) error {
+	c.httpClient = c.config.Client(ctx, tok)
+
+	srv, err := calendar.New(c.httpClient)
+	if err != nil {
+		return fmt.Errorf("calclient: unable to create calendar service: %w", err)
+	}
+
+	c.srv = srv
+
+	return nil
+}
+
+// Service returns the authenticated calendar.Service. It panics if
+// called before a successful Authenticate, which is a programmer error.
+func (c *CalClient) Service() *calendar.Service {
+	if c.srv == nil {
+		panic("calclient: Service called before Authenticate")
+	}
+
+	return c.srv
+}
+
+// memoryTokenStore is a trivial TokenStore that never persists anything
+// across process restarts. It is mainly useful for tests and for
+// embedders that manage their own token persistence out of band.
+type memoryTokenStore struct {
+	tok *oauth2.Token
+}
+
+// NewMemoryTokenStore returns a TokenStore that keeps the token in
+// memory only, for the lifetime of the process.
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{}
+}
+
+func (m *memoryTokenStore) Load() (*oauth2.Token, error) {
+	if m.tok == nil {
+		return nil, fmt.Errorf("calclient: no token in memory store")
+	}
+
+	return m.tok, nil
+}
+
+func (m *memoryTokenStore) Save(token *oauth2.Token) error {
+	m.tok = token
+
+	return nil
+}
+
+// tokenJSON is used solely to round-trip an oauth2.Token through the
+// same encoding the old file-based cache used, so existing cached
+// tokens keep working.
+func decodeToken(data []byte) (*oauth2.Token, error) {
+	tok := &oauth2.Token{}
+	if err := json.Unmarshal(data, tok); err != nil {
+		return nil, err
+	}
+
+	return tok, nil
+}
+
+func encodeToken(tok *oauth2.Token) ([]byte, error) {
+	return json.Marshal(tok)
+}
@@ -0,0 +1,45 @@
+package calclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/oauth2"
+)
+
+// FileTokenStore is a TokenStore backed by a single JSON file on disk,
+// matching the cache format used by earlier, non-packaged versions of
+// this tool.
+type FileTokenStore struct {
+	// Path is the file the token is read from and written to.
+	Path string
+}
+
+// NewFileTokenStore returns a TokenStore that persists the token as
+// JSON at path, creating the file (mode 0600) on first Save.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+func (f *FileTokenStore) Load() (*oauth2.Token, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeToken(data)
+}
+
+func (f *FileTokenStore) Save(token *oauth2.Token) error {
+	data, err := encodeToken(token)
+	if err != nil {
+		return fmt.Errorf("calclient: unable to encode token: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0700); err != nil {
+		return fmt.Errorf("calclient: unable to create token directory: %w", err)
+	}
+
+	return os.WriteFile(f.Path, data, 0600)
+}
@@ -0,0 +1,127 @@
+package calclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+
+	"golang.org/x/oauth2"
+)
+
+// browserOpener returns a function that opens a URL in the user's
+// default browser, and whether one could be found for the current
+// platform. It's false when there's nothing to exec (e.g. a minimal
+// container or a mobile build), signalling callers to use the manual
+// flow instead.
+func browserOpener() (func(url string) error, bool) {
+	var name string
+	var args []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		name = "rundll32"
+		args = []string{"url.dll,FileProtocolHandler"}
+	default:
+		name = "xdg-open"
+	}
+
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, false
+	}
+
+	return func(url string) error {
+		return exec.Command(name, append(args, url)...).Start()
+	}, true
+}
+
+// getTokenViaLoopback runs the installed-app loopback flow: it listens
+// on 127.0.0.1 on a random port, points the OAuth2 redirect there,
+// opens the consent page in the browser, and waits for Google to
+// redirect back with the authorization code.
+func getTokenViaLoopback(ctx context.Context, config *oauth2.Config, openBrowser func(string) error) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for OAuth2 callback: %w", err)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	origRedirectURL := config.RedirectURL
+	config.RedirectURL = fmt.Sprintf("http://%s/", listener.Addr().String())
+	defer func() { config.RedirectURL = origRedirectURL }()
+
+	type result struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if errMsg := query.Get("error"); errMsg != "" {
+			resultCh <- result{err: fmt.Errorf("authorization denied: %s", errMsg)}
+			fmt.Fprint(w, "Authorization failed, you can close this tab.")
+			return
+		}
+
+		if got := query.Get("state"); got != state {
+			resultCh <- result{err: fmt.Errorf("state mismatch: got %q", got)}
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			return
+		}
+
+		code := query.Get("code")
+		if code == "" {
+			resultCh <- result{err: fmt.Errorf("no authorization code in callback")}
+			http.Error(w, "missing code", http.StatusBadRequest)
+			return
+		}
+
+		resultCh <- result{code: code}
+		fmt.Fprint(w, "Authorization complete, you can close this tab.")
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	authURL := config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	if err := openBrowser(authURL); err != nil {
+		return nil, fmt.Errorf("unable to open browser: %w", err)
+	}
+
+	fmt.Printf("Your browser has been opened to visit:\n%v\n", authURL)
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil {
+			return nil, res.err
+		}
+
+		return config.Exchange(ctx, res.code)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate state: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,156 @@
+package calclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeConfig returns an oauth2.Config good enough to exercise the
+// loopback server without talking to Google: AuthCodeURL/Exchange only
+// need an AuthURL/TokenURL to format against.
+func fakeConfig() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  "https://example.invalid/auth",
+			TokenURL: "https://example.invalid/token",
+		},
+	}
+}
+
+// redirectURIFrom extracts the loopback redirect_uri the server embedded
+// in the Google-bound auth URL, so a test can hit the callback directly
+// without a real browser.
+func redirectURIFrom(t *testing.T, authURL string) string {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authURL: %v", err)
+	}
+
+	redirect := u.Query().Get("redirect_uri")
+	if redirect == "" {
+		t.Fatalf("authURL %q has no redirect_uri", authURL)
+	}
+
+	return redirect
+}
+
+func TestGetTokenViaLoopbackRejectsStateMismatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	openBrowser := func(authURL string) error {
+		go func() {
+			redirect := redirectURIFrom(t, authURL)
+
+			resp, err := http.Get(redirect + "?state=wrong-state&code=irrelevant")
+			if err != nil {
+				return
+			}
+			resp.Body.Close()
+		}()
+
+		return nil
+	}
+
+	go func() {
+		_, err := getTokenViaLoopback(ctx, fakeConfig(), openBrowser)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "state mismatch") {
+			t.Fatalf("got error %v, want a state-mismatch error", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for loopback result")
+	}
+}
+
+func TestGetTokenViaLoopbackSurfacesAuthorizationError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	openBrowser := func(authURL string) error {
+		go func() {
+			redirect := redirectURIFrom(t, authURL)
+
+			resp, err := http.Get(redirect + "?error=access_denied")
+			if err != nil {
+				return
+			}
+			resp.Body.Close()
+		}()
+
+		return nil
+	}
+
+	go func() {
+		_, err := getTokenViaLoopback(ctx, fakeConfig(), openBrowser)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "authorization denied") {
+			t.Fatalf("got error %v, want an authorization-denied error", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for loopback result")
+	}
+}
+
+func TestGetTokenViaLoopbackRejectsMissingCode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	openBrowser := func(authURL string) error {
+		go func() {
+			u, err := url.Parse(authURL)
+			if err != nil {
+				return
+			}
+
+			state := u.Query().Get("state")
+			redirect := redirectURIFrom(t, authURL)
+
+			resp, err := http.Get(redirect + "?state=" + state)
+			if err != nil {
+				return
+			}
+			resp.Body.Close()
+		}()
+
+		return nil
+	}
+
+	go func() {
+		_, err := getTokenViaLoopback(ctx, fakeConfig(), openBrowser)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "no authorization code") {
+			t.Fatalf("got error %v, want a missing-code error", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for loopback result")
+	}
+}
@@ -0,0 +1,50 @@
+package calclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// getTokenFromWeb sends the user through the OAuth2 consent flow and
+// returns the resulting Token. It prefers a loopback redirect so the
+// code is exchanged automatically, falling back to having the user
+// paste the authorization code back when no browser can be opened
+// (e.g. over SSH, or when embedded on a platform with no launchable
+// browser).
+func getTokenFromWeb(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	opener, ok := browserOpener()
+	if ok {
+		tok, err := getTokenViaLoopback(ctx, config, opener)
+		if err == nil {
+			return tok, nil
+		}
+
+		fmt.Fprintf(os.Stderr, "calclient: loopback auth failed (%v), falling back to manual code entry\n", err)
+	}
+
+	return getTokenViaManualEntry(ctx, config)
+}
+
+// getTokenViaManualEntry prints the consent URL and reads the
+// authorization code the user pastes back on stdin. It's the original,
+// always-available flow, kept as a fallback for terminals with no
+// browser to open.
+func getTokenViaManualEntry(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	fmt.Printf("Go to the following link in your browser then type the authorization code: \n%v\n", authURL)
+
+	var code string
+	if _, err := fmt.Scan(&code); err != nil {
+		return nil, fmt.Errorf("unable to read authorization code: %w", err)
+	}
+
+	tok, err := config.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return tok, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lootek/gomobile-gcal-summary/export"
+)
+
+// runExport is the "export" subcommand: dumps matched events between
+// -since and -until as JSON, CSV or iCalendar.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+
+	format := fs.String("format", "json", "output format: json, csv or ical")
+
+	now := time.Now()
+	r := &timeRange{}
+	r.register(fs, now.AddDate(0, 0, -7), now)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	events, err := fetchMatchedEvents(context.Background(), r)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		return export.JSON(os.Stdout, events)
+	case "csv":
+		return export.CSV(os.Stdout, events)
+	case "ical":
+		return export.ICal(os.Stdout, events)
+	default:
+		return fmt.Errorf("unknown -format %q (want json, csv or ical)", *format)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lootek/gomobile-gcal-summary/freebusy"
+)
+
+// runFreebusy is the "freebusy" subcommand: prints a per-day free/busy
+// map for matched events between -since and -until.
+func runFreebusy(args []string) error {
+	fs := flag.NewFlagSet("freebusy", flag.ExitOnError)
+
+	now := time.Now()
+	r := &timeRange{}
+	r.register(fs, now.AddDate(0, 0, -7), now)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	since, until, err := r.bounds()
+	if err != nil {
+		return err
+	}
+
+	events, err := fetchMatchedEvents(context.Background(), r)
+	if err != nil {
+		return err
+	}
+
+	busy := freebusy.Compute(events, since, until)
+
+	days := make([]string, 0, len(busy))
+	for day := range busy {
+		days = append(days, day)
+	}
+
+	sort.Strings(days)
+
+	for _, day := range days {
+		fmt.Printf("%s:\n", day)
+
+		for _, w := range busy[day] {
+			fmt.Printf("\t%s - %s\n", w.Start.Format("15:04"), w.End.Format("15:04"))
+		}
+	}
+
+	return nil
+}
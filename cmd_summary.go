@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/lootek/gomobile-gcal-summary/timetracker"
+)
+
+// runSummary is the "summary" subcommand: the tool's original
+// behavior of printing week/month/quarter/year totals against target
+// hours, now backed by the scriptable -since/-until/-calendar-id/-match
+// flags.
+func runSummary(args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+
+	now := time.Now()
+	defaultSince := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	defaultUntil := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+
+	r := &timeRange{}
+	r.register(fs, defaultSince, defaultUntil)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	matchers := r.matchers()
+	if len(matchers) == 0 {
+		matchers = []timetracker.Matcher{timetracker.PrefixMatcher{Prefix: "SolarWinds"}}
+	}
+
+	since, until, err := r.bounds()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	agg, err := aggregator(ctx)
+	if err != nil {
+		return err
+	}
+
+	events, err := agg.FetchEvents(ctx, since, until)
+	if err != nil {
+		return fmt.Errorf("unable to fetch events: %w", err)
+	}
+
+	tracker := timetracker.New(timetracker.Config{
+		Matchers:         matchers,
+		TargetHours:      timetracker.DefaultTargetHours(),
+		WeekStart:        time.Monday,
+		AllDayEventHours: 8,
+	})
+
+	report := tracker.Summarize(events, now)
+
+	for _, day := range report.Days {
+		fmt.Printf("%v\t\t%.2f\n", day.Date.Format(time.RFC1123), day.Total)
+	}
+
+	fmt.Printf("week total: %.2f of %.2f (%+.2f)\n", report.Week.Total, report.Week.Target, report.Week.Delta)
+	fmt.Printf("month total: %.2f of %.2f (%+.2f)\n", report.Month.Total, report.Month.Target, report.Month.Delta)
+	fmt.Printf("quarter total: %.2f of %.2f (%+.2f)\n", report.Quarter.Total, report.Quarter.Target, report.Quarter.Delta)
+	fmt.Printf("year total: %.2f of %.2f (%+.2f)\n", report.Year.Total, report.Year.Target, report.Year.Delta)
+
+	return nil
+}
@@ -0,0 +1,190 @@
+// Package export writes matched calendar events out in formats other
+// tools can consume: JSON for scripting, CSV for spreadsheets, and
+// iCalendar for importing into other calendars.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lootek/gomobile-gcal-summary/timetracker"
+)
+
+// record is one exported event, with start/end/duration already
+// resolved so JSON and CSV share the same fields.
+type record struct {
+	Date       string  `json:"date"`
+	Start      string  `json:"start"`
+	End        string  `json:"end"`
+	DurationHr float64 `json:"duration_hours"`
+	CalendarID string  `json:"calendar_id"`
+	Summary    string  `json:"summary"`
+}
+
+func toRecords(events []timetracker.Event) []record {
+	records := make([]record, 0, len(events))
+
+	for _, ev := range events {
+		if ev.Event.Status == "cancelled" {
+			continue
+		}
+
+		rec := record{CalendarID: ev.CalendarID, Summary: ev.Event.Summary}
+
+		switch {
+		case ev.Event.Start != nil && ev.Event.Start.DateTime != "":
+			start, err := time.Parse(time.RFC3339, ev.Event.Start.DateTime)
+			if err != nil {
+				continue
+			}
+
+			end, err := time.Parse(time.RFC3339, ev.Event.End.DateTime)
+			if err != nil {
+				continue
+			}
+
+			rec.Date = start.Format("2006-01-02")
+			rec.Start = start.Format(time.RFC3339)
+			rec.End = end.Format(time.RFC3339)
+			rec.DurationHr = end.Sub(start).Hours()
+		case ev.Event.Start != nil && ev.Event.Start.Date != "":
+			rec.Date = ev.Event.Start.Date
+			rec.Start = ev.Event.Start.Date
+			rec.End = ev.Event.End.Date
+		default:
+			continue
+		}
+
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+// JSON writes events as a JSON array to w.
+func JSON(w io.Writer, events []timetracker.Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(toRecords(events))
+}
+
+// CSV writes events to w with columns
+// date,start,end,duration,calendar,summary.
+func CSV(w io.Writer, events []timetracker.Event) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"date", "start", "end", "duration", "calendar", "summary"}); err != nil {
+		return err
+	}
+
+	for _, rec := range toRecords(events) {
+		row := []string{
+			rec.Date,
+			rec.Start,
+			rec.End,
+			fmt.Sprintf("%.2f", rec.DurationHr),
+			rec.CalendarID,
+			rec.Summary,
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ICal writes events to w as a minimal iCalendar (.ics) document.
+func ICal(w io.Writer, events []timetracker.Event) error {
+	if _, err := fmt.Fprint(w, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//gomobile-gcal-summary//EN\r\n"); err != nil {
+		return err
+	}
+
+	for i, ev := range events {
+		if ev.Event.Status == "cancelled" {
+			continue
+		}
+
+		if ev.Event.Start == nil || ev.Event.End == nil {
+			continue
+		}
+
+		var dtstart, dtend string
+
+		switch {
+		case ev.Event.Start.DateTime != "":
+			start, err := time.Parse(time.RFC3339, ev.Event.Start.DateTime)
+			if err != nil {
+				continue
+			}
+
+			end, err := time.Parse(time.RFC3339, ev.Event.End.DateTime)
+			if err != nil {
+				continue
+			}
+
+			dtstart = "DTSTART:" + start.UTC().Format("20060102T150405Z")
+			dtend = "DTEND:" + end.UTC().Format("20060102T150405Z")
+		case ev.Event.Start.Date != "":
+			start, err := time.Parse("2006-01-02", ev.Event.Start.Date)
+			if err != nil {
+				continue
+			}
+
+			end, err := time.Parse("2006-01-02", ev.Event.End.Date)
+			if err != nil {
+				continue
+			}
+
+			dtstart = "DTSTART;VALUE=DATE:" + start.Format("20060102")
+			dtend = "DTEND;VALUE=DATE:" + end.Format("20060102")
+		default:
+			continue
+		}
+
+		uid := ev.Event.Id
+		if uid == "" {
+			uid = fmt.Sprintf("export-%d@gomobile-gcal-summary", i)
+		}
+
+		if _, err := fmt.Fprintf(w, "BEGIN:VEVENT\r\nUID:%s\r\n%s\r\n%s\r\nSUMMARY:%s\r\nEND:VEVENT\r\n",
+			uid, dtstart, dtend, icalEscape(ev.Event.Summary)); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprint(w, "END:VCALENDAR\r\n")
+
+	return err
+}
+
+// icalEscape escapes s per RFC 5545 section 3.3.11: backslash, comma
+// and semicolon are backslash-escaped, and embedded newlines become
+// the literal two-character sequence "\n" rather than a real line
+// break, since a bare CR/LF would otherwise start a new content line
+// with no property name.
+func icalEscape(s string) string {
+	var out []rune
+
+	for _, r := range s {
+		switch r {
+		case ',', ';', '\\':
+			out = append(out, '\\', r)
+		case '\r':
+			// dropped; a following '\n' (or one on its own) renders the break
+		case '\n':
+			out = append(out, '\\', 'n')
+		default:
+			out = append(out, r)
+		}
+	}
+
+	return string(out)
+}
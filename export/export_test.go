@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	calendar "google.golang.org/api/calendar/v3"
+
+	"github.com/lootek/gomobile-gcal-summary/timetracker"
+)
+
+func timedEvent(id, summary, status, start, end string) timetracker.Event {
+	return timetracker.Event{
+		CalendarID: "cal1",
+		Event: &calendar.Event{
+			Id:      id,
+			Summary: summary,
+			Status:  status,
+			Start:   &calendar.EventDateTime{DateTime: start},
+			End:     &calendar.EventDateTime{DateTime: end},
+		},
+	}
+}
+
+func TestJSONSkipsCancelledEvents(t *testing.T) {
+	events := []timetracker.Event{
+		timedEvent("1", "Kept", "confirmed", "2026-07-22T09:00:00Z", "2026-07-22T10:00:00Z"),
+		timedEvent("2", "Dropped", "cancelled", "2026-07-22T09:00:00Z", "2026-07-22T10:00:00Z"),
+	}
+
+	var buf bytes.Buffer
+	if err := JSON(&buf, events); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "Dropped") {
+		t.Errorf("JSON output included a cancelled event: %s", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "Kept") {
+		t.Errorf("JSON output missing the non-cancelled event: %s", buf.String())
+	}
+}
+
+func TestCSVColumns(t *testing.T) {
+	events := []timetracker.Event{
+		timedEvent("1", "Standup", "confirmed", "2026-07-22T09:00:00Z", "2026-07-22T09:30:00Z"),
+	}
+
+	var buf bytes.Buffer
+	if err := CSV(&buf, events); err != nil {
+		t.Fatalf("CSV: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (header + 1 row): %q", len(lines), buf.String())
+	}
+
+	const wantHeader = "date,start,end,duration,calendar,summary"
+	if lines[0] != wantHeader {
+		t.Errorf("header = %q, want %q", lines[0], wantHeader)
+	}
+
+	if !strings.Contains(lines[1], "0.50") || !strings.Contains(lines[1], "Standup") {
+		t.Errorf("row = %q, want duration 0.50 and summary Standup", lines[1])
+	}
+}
+
+func TestICalSkipsCancelledAndEscapesNewlines(t *testing.T) {
+	events := []timetracker.Event{
+		timedEvent("1", "Line1\nLine2", "confirmed", "2026-07-22T09:00:00Z", "2026-07-22T10:00:00Z"),
+		timedEvent("2", "Dropped", "cancelled", "2026-07-22T09:00:00Z", "2026-07-22T10:00:00Z"),
+	}
+
+	var buf bytes.Buffer
+	if err := ICal(&buf, events); err != nil {
+		t.Fatalf("ICal: %v", err)
+	}
+
+	out := buf.String()
+
+	if strings.Contains(out, "Dropped") {
+		t.Errorf("ICal output included a cancelled event: %s", out)
+	}
+
+	if !strings.Contains(out, "SUMMARY:Line1\\nLine2\r\n") {
+		t.Errorf("ICal output did not escape the embedded newline as a literal \\n: %s", out)
+	}
+
+	if strings.Contains(out, "SUMMARY:Line1\r\n") {
+		t.Errorf("ICal output left a bare CR/LF inside SUMMARY, which breaks the content line: %s", out)
+	}
+}
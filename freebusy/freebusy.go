@@ -0,0 +1,149 @@
+// Package freebusy turns a list of calendar events into a per-day
+// free/busy map, merging overlapping events the same way timetracker
+// does when summing hours.
+package freebusy
+
+import (
+	"sort"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+
+	"github.com/lootek/gomobile-gcal-summary/timetracker"
+)
+
+// Window is a merged busy interval within a single day.
+type Window struct {
+	Start, End time.Time
+}
+
+const dayKeyLayout = "2006-01-02"
+
+// Compute returns the merged busy windows for each day between since
+// and until, keyed by "2006-01-02". All-day events mark the entire day
+// busy.
+func Compute(events []timetracker.Event, since, until time.Time) map[string][]Window {
+	dayWindows := map[string][]Window{}
+
+	for _, ev := range events {
+		if ev.Event.Status == "cancelled" {
+			continue
+		}
+
+		addBusy(ev.Event, since, until, dayWindows)
+	}
+
+	for day, windows := range dayWindows {
+		dayWindows[day] = mergeWindows(windows)
+	}
+
+	return dayWindows
+}
+
+func addBusy(ev *calendar.Event, since, until time.Time, dayWindows map[string][]Window) {
+	if ev.Start == nil || ev.End == nil {
+		return
+	}
+
+	if ev.Start.DateTime != "" && ev.End.DateTime != "" {
+		start, err := time.Parse(time.RFC3339, ev.Start.DateTime)
+		if err != nil {
+			return
+		}
+
+		end, err := time.Parse(time.RFC3339, ev.End.DateTime)
+		if err != nil {
+			return
+		}
+
+		start, end = clip(start, end, since, until)
+		if !start.Before(end) {
+			return
+		}
+
+		for day := startOfDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+			dayStart := maxTime(day, start)
+			dayEnd := minTime(day.AddDate(0, 0, 1), end)
+
+			if !dayStart.Before(dayEnd) {
+				continue
+			}
+
+			key := day.Format(dayKeyLayout)
+			dayWindows[key] = append(dayWindows[key], Window{Start: dayStart, End: dayEnd})
+		}
+
+		return
+	}
+
+	if ev.Start.Date != "" && ev.End.Date != "" {
+		startDate, err := time.ParseInLocation(dayKeyLayout, ev.Start.Date, time.Local)
+		if err != nil {
+			return
+		}
+
+		endDate, err := time.ParseInLocation(dayKeyLayout, ev.End.Date, time.Local)
+		if err != nil {
+			return
+		}
+
+		startDate, endDate = clip(startDate, endDate, since, until)
+
+		for day := startDate; day.Before(endDate); day = day.AddDate(0, 0, 1) {
+			key := day.Format(dayKeyLayout)
+			dayWindows[key] = append(dayWindows[key], Window{Start: day, End: day.AddDate(0, 0, 1)})
+		}
+	}
+}
+
+func mergeWindows(windows []Window) []Window {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	sorted := make([]Window, len(windows))
+	copy(sorted, windows)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	merged := []Window{sorted[0]}
+
+	for _, w := range sorted[1:] {
+		last := &merged[len(merged)-1]
+
+		if !w.Start.After(last.End) {
+			if w.End.After(last.End) {
+				last.End = w.End
+			}
+
+			continue
+		}
+
+		merged = append(merged, w)
+	}
+
+	return merged
+}
+
+func clip(start, end, since, until time.Time) (time.Time, time.Time) {
+	return maxTime(start, since), minTime(end, until)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
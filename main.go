@@ -4,11 +4,10 @@ package main
 // https://godoc.org/google.golang.org/api/calendar/v3#EventsService
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"net/url"
 	"os"
 	"os/user"
@@ -16,214 +15,263 @@ import (
 	"strings"
 	"time"
 
-	"golang.org/x/net/context"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 	calendar "google.golang.org/api/calendar/v3"
+
+	"github.com/lootek/gomobile-gcal-summary/accounts"
+	"github.com/lootek/gomobile-gcal-summary/cache"
+	"github.com/lootek/gomobile-gcal-summary/calclient"
+	"github.com/lootek/gomobile-gcal-summary/timetracker"
 )
 
-// getClient uses a Context and Config to retrieve a Token
-// then generate a Client. It returns the generated Client.
-func getClient(ctx context.Context, config *oauth2.Config) *http.Client {
-	cacheFile, err := tokenCacheFile()
+const accountsConfigFile = "accounts.json"
+
+// tokenCacheFile generates credential file path/filename.
+// It returns the generated credential path/filename.
+func tokenCacheFile() (string, error) {
+	usr, err := user.Current()
 	if err != nil {
-		log.Fatalf("Unable to get path to cached credential file. %v", err)
+		return "", err
 	}
 
-	tok, err := tokenFromFile(cacheFile)
+	tokenCacheDir := filepath.Join(usr.HomeDir, ".credentials")
+
+	os.MkdirAll(tokenCacheDir, 0700)
+
+	return filepath.Join(tokenCacheDir, url.QueryEscape("calendar-go-quickstart.json")), err
+}
+
+// responseCacheDir returns where calendar/event API responses are
+// cached, alongside the token cache.
+func responseCacheDir() (string, error) {
+	usr, err := user.Current()
 	if err != nil {
-		tok = getTokenFromWeb(config)
-		saveToken(cacheFile, tok)
+		return "", err
 	}
 
-	return config.Client(ctx, tok)
+	return filepath.Join(usr.HomeDir, ".credentials", "cache"), nil
 }
 
-// getTokenFromWeb uses Config to request a Token.
-// It returns the retrieved Token.
-func getTokenFromWeb(config *oauth2.Config) *oauth2.Token {
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser then type the authorization code: \n%v\n", authURL)
-
-	var code string
-	if _, err := fmt.Scan(&code); err != nil {
-		log.Fatalf("Unable to read authorization code %v", err)
+// loadAccounts returns the accounts to aggregate: the ones listed in
+// accounts.json if present, otherwise a single account built from
+// client_secret.json, matching the tool's original single-account
+// behavior.
+func loadAccounts() ([]*accounts.Account, error) {
+	if cfg, err := accounts.LoadConfig(accountsConfigFile); err == nil {
+		return cfg.Load()
 	}
 
-	tok, err := config.Exchange(oauth2.NoContext, code)
+	secret, err := os.ReadFile("client_secret.json")
 	if err != nil {
-		log.Fatalf("Unable to retrieve token from web %v", err)
+		return nil, fmt.Errorf("unable to read client secret file: %w", err)
 	}
 
-	return tok
-}
+	cacheFile, err := tokenCacheFile()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get path to cached credential file: %w", err)
+	}
 
-// tokenCacheFile generates credential file path/filename.
-// It returns the generated credential path/filename.
-func tokenCacheFile() (string, error) {
-	usr, err := user.Current()
+	// If modifying the scopes requested in calclient, delete the
+	// previously saved credentials at cacheFile.
+	cc, err := calclient.New(secret, calclient.NewFileTokenStore(cacheFile))
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("unable to parse client secret file to config: %w", err)
 	}
 
-	tokenCacheDir := filepath.Join(usr.HomeDir, ".credentials")
+	return []*accounts.Account{accounts.NewAccount("default", cc)}, nil
+}
 
-	os.MkdirAll(tokenCacheDir, 0700)
+// aggregator authenticates every loaded account and returns an
+// accounts.Aggregator ready to fetch events for them.
+func aggregator(ctx context.Context) (*accounts.Aggregator, error) {
+	accs, err := loadAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load accounts: %w", err)
+	}
 
-	return filepath.Join(tokenCacheDir, url.QueryEscape("calendar-go-quickstart.json")), err
-}
+	for _, acc := range accs {
+		if err := acc.Authenticate(ctx); err != nil {
+			return nil, fmt.Errorf("unable to authenticate account %q: %w", acc.ID, err)
+		}
+	}
 
-// tokenFromFile retrieves a Token from a given file path.
-// It returns the retrieved Token and any read error encountered.
-func tokenFromFile(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
-	defer f.Close()
+	cacheDir, err := responseCacheDir()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to get path to response cache: %w", err)
 	}
 
-	t := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(t)
+	store, err := cache.NewStore(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open response cache: %w", err)
+	}
 
-	return t, err
+	return accounts.NewAggregator(accs, store), nil
 }
 
-// saveToken uses a file path to create a file and store the
-// token in it.
-func saveToken(file string, token *oauth2.Token) {
-	fmt.Printf("Saving credential file to: %s\n", file)
+func usage() {
+	fmt.Fprintf(os.Stderr, `usage: %s <command> [flags]
 
-	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	defer f.Close()
-	if err != nil {
-		log.Fatalf("Unable to cache oauth token: %v", err)
-	}
+Commands:
+  summary    print week/month/quarter/year totals against target hours
+  export     dump matched events as JSON, CSV or iCalendar
+  freebusy   print a per-day free/busy map
 
-	json.NewEncoder(f).Encode(token)
+Run "%s <command> -h" for the flags a command accepts.
+`, os.Args[0], os.Args[0])
 }
 
 func main() {
-	ctx := context.Background()
-
-	b, err := ioutil.ReadFile("client_secret.json")
-	if err != nil {
-		log.Fatalf("Unable to read client secret file: %v", err)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
 	}
 
-	// If modifying these scopes, delete your previously saved credentials
-	// at ~/.credentials/calendar-go-quickstart.json
-	config, err := google.ConfigFromJSON(b, calendar.CalendarReadonlyScope)
-	if err != nil {
-		log.Fatalf("Unable to parse client secret file to config: %v", err)
+	var err error
+
+	switch os.Args[1] {
+	case "summary":
+		err = runSummary(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "freebusy":
+		err = runFreebusy(os.Args[2:])
+	case "-h", "--help", "help":
+		usage()
+		return
+	default:
+		usage()
+		os.Exit(2)
 	}
 
-	client := getClient(ctx, config)
-	srv, err := calendar.New(client)
 	if err != nil {
-		log.Fatalf("Unable to retrieve calendar Client %v", err)
+		log.Fatal(err)
 	}
+}
 
-	list, err := srv.CalendarList.List().ShowHidden(false).Do()
-	if err != nil || list == nil {
-		log.Fatalf("Unable to retrieve user's calendars list. %v", err)
-	}
+// timeRange holds the --since/--until/--calendar-id/--match flags
+// shared by every subcommand.
+type timeRange struct {
+	since       string
+	until       string
+	calendarIDs string
+	match       string
+}
 
-	now := time.Now()
+func (r *timeRange) register(fs *flag.FlagSet, defaultSince, defaultUntil time.Time) {
+	fs.StringVar(&r.since, "since", defaultSince.Format("2006-01-02"), "start date (YYYY-MM-DD)")
+	fs.StringVar(&r.until, "until", defaultUntil.Format("2006-01-02"), "end date (YYYY-MM-DD)")
+	fs.StringVar(&r.calendarIDs, "calendar-id", "", "comma-separated calendar IDs to include")
+	fs.StringVar(&r.match, "match", "", "comma-separated summary prefixes to match")
+}
 
-	monthBegin := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-	monthEnd := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
-	fmt.Printf("%v - %v\n", monthBegin, monthEnd)
+// bounds parses since/until into a [start, end-of-day] time range.
+func (r *timeRange) bounds() (time.Time, time.Time, error) {
+	since, err := time.ParseInLocation("2006-01-02", r.since, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid -since: %w", err)
+	}
 
-	weekday := int(now.Weekday())
-	if weekday == 0 {
-		weekday = 7
+	until, err := time.ParseInLocation("2006-01-02", r.until, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid -until: %w", err)
 	}
 
-	weekBegin := time.Date(now.Year(), now.Month(), now.Day()-weekday+1, 0, 0, 0, 0, now.Location())
-	weekEnd := time.Date(now.Year(), now.Month(), now.Day()-weekday+7, 23, 59, 59, 0, now.Location())
-	fmt.Printf("%v - %v\n", weekBegin, weekEnd)
+	until = time.Date(until.Year(), until.Month(), until.Day(), 23, 59, 59, 0, until.Location())
 
-	var lastStartTime, lastEndTime time.Time
+	return since, until, nil
+}
 
-	today := 0
+// flagMatcher matches events by -match prefix (if any) AND -calendar-id
+// (if any): both given flags must be satisfied, unlike TimeTracker's
+// own Matchers, which are OR'd together.
+type flagMatcher struct {
+	prefixes    []string
+	calendarIDs []string
+}
 
-	weekTotal := 0.0
-	workDaysInWeek := 0
-	monthTotal := 0.0
-	workDaysInMonth := 0
+func (m flagMatcher) Matches(calendarID string, ev *calendar.Event) bool {
+	if len(m.calendarIDs) > 0 {
+		included := false
 
-	for _, cal := range list.Items {
-		// fmt.Printf("%s%#v\n\n", strings.Repeat("=", 100), cal)
+		for _, id := range m.calendarIDs {
+			if id == calendarID {
+				included = true
+				break
+			}
+		}
 
-		// events, err := srv.Events.List(cal.Id).ShowDeleted(false).SingleEvents(true).TimeMin(monthBegin.Format(time.RFC3339)).TimeMax(monthEnd.Format(time.RFC3339)).OrderBy("startTime").Do()
-		events, err := srv.Events.List(cal.Id).ShowDeleted(false).SingleEvents(true).TimeMin(time.Date(now.Year(), 0, 0, 0, 0, 0, 0, now.Location()).Format(time.RFC3339)).TimeMax(monthEnd.Format(time.RFC3339)).OrderBy("startTime").Do()
-		if err != nil {
-			log.Fatalf("Unable to retrieve next ten of the user's events. %v", err)
+		if !included {
+			return false
 		}
+	}
 
-		for _, ev := range events.Items {
-			if !strings.HasPrefix(ev.Summary, "SolarWinds") {
-				continue
-			}
+	if len(m.prefixes) == 0 {
+		return true
+	}
 
-			startTime, err := time.Parse(time.RFC3339, ev.Start.DateTime)
-			if err != nil {
-				fmt.Println(err)
-			}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(ev.Summary, prefix) {
+			return true
+		}
+	}
 
-			endTime, err := time.Parse(time.RFC3339, ev.End.DateTime)
-			if err != nil {
-				fmt.Println(err)
-			}
+	return false
+}
 
-			inWeek := false
-			inMonth := false
+// matchers builds the Matcher list -calendar-id and -match describe.
+func (r *timeRange) matchers() []timetracker.Matcher {
+	if r.match == "" && r.calendarIDs == "" {
+		return nil
+	}
 
-			if startTime.Unix() > weekBegin.Unix() && endTime.Unix() < weekEnd.Unix() {
-				inWeek = true
-			}
+	m := flagMatcher{}
 
-			if startTime.Unix() > monthBegin.Unix() && endTime.Unix() < monthEnd.Unix() {
-				inMonth = true
-			}
+	if r.match != "" {
+		for _, prefix := range strings.Split(r.match, ",") {
+			m.prefixes = append(m.prefixes, strings.TrimSpace(prefix))
+		}
+	}
 
-			duration := endTime.Sub(startTime).Hours()
+	if r.calendarIDs != "" {
+		for _, id := range strings.Split(r.calendarIDs, ",") {
+			m.calendarIDs = append(m.calendarIDs, strings.TrimSpace(id))
+		}
+	}
 
-			if startTime.Day() != today {
-				today = startTime.Day()
+	return []timetracker.Matcher{m}
+}
 
-				lastStartTime = startTime
-				lastEndTime = endTime
+// fetchMatchedEvents fetches events in [since, until] and filters them
+// down to the ones matching r.
+func fetchMatchedEvents(ctx context.Context, r *timeRange) ([]timetracker.Event, error) {
+	since, until, err := r.bounds()
+	if err != nil {
+		return nil, err
+	}
 
-				if inWeek {
-					workDaysInWeek += 1
-				}
+	agg, err := aggregator(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-				if inMonth {
-					workDaysInMonth += 1
-				}
-				// } else if startTime.Unix() < lastEndTime.Unix() {
-				// 	duration = endTime.Sub(lastEndTime).Hours()
-			}
+	events, err := agg.FetchEvents(ctx, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch events: %w", err)
+	}
 
-			if inWeek {
-				weekTotal += duration
-			}
+	matchers := r.matchers()
+	if len(matchers) == 0 {
+		return events, nil
+	}
 
-			if inMonth {
-				monthTotal += duration
+	var matched []timetracker.Event
+	for _, ev := range events {
+		for _, m := range matchers {
+			if m.Matches(ev.CalendarID, ev.Event) {
+				matched = append(matched, ev)
+				break
 			}
-
-			fmt.Printf("%v\t\t%v\t%v\n\n", startTime.Format(time.RFC1123), duration, ev.Summary)
 		}
 	}
 
-	_ = lastStartTime
-	_ = lastEndTime
-
-	weekTargetTotal := float64(workDaysInWeek * 8)
-	monthTargetTotal := float64(workDaysInMonth * 8)
-
-	fmt.Printf("week total: %v of %v (%+.2f)\n", weekTotal, weekTargetTotal, -(weekTargetTotal - weekTotal))
-	fmt.Printf("month total: %v of %v (%+.2f)\n", monthTotal, monthTargetTotal, -(monthTargetTotal - monthTotal))
+	return matched, nil
 }
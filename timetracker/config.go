@@ -0,0 +1,38 @@
+package timetracker
+
+import "time"
+
+// Config configures a TimeTracker.
+type Config struct {
+	// Matchers selects which events count towards tracked time. An
+	// event is counted if it matches at least one Matcher. An empty
+	// Matchers matches every event.
+	Matchers []Matcher
+
+	// TargetHours maps a weekday to the number of hours expected on
+	// that day, e.g. to give Fridays a shorter target or weekends none.
+	// Weekdays missing from the map default to 0.
+	TargetHours map[time.Weekday]float64
+
+	// WeekStart is the first day of the week used when computing week
+	// boundaries, typically time.Monday or time.Sunday.
+	WeekStart time.Weekday
+
+	// AllDayEventHours is the fixed duration credited to an all-day
+	// event (one with Start.Date/End.Date instead of a DateTime).
+	AllDayEventHours float64
+}
+
+// DefaultTargetHours returns an 8-hour, Monday-to-Friday target with
+// weekends at 0, matching the tool's original fixed 8h/day assumption.
+func DefaultTargetHours() map[time.Weekday]float64 {
+	return map[time.Weekday]float64{
+		time.Monday:    8,
+		time.Tuesday:   8,
+		time.Wednesday: 8,
+		time.Thursday:  8,
+		time.Friday:    8,
+		time.Saturday:  0,
+		time.Sunday:    0,
+	}
+}
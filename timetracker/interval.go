@@ -0,0 +1,54 @@
+package timetracker
+
+import (
+	"sort"
+	"time"
+)
+
+// interval is a clock-time span within a single calendar day, used to
+// de-duplicate overlapping events before summing hours.
+type interval struct {
+	start, end time.Time
+}
+
+// mergeIntervals sorts intervals by start and merges any that overlap
+// or touch, so two "SolarWinds" meetings double-booked over the same
+// hour count once rather than twice.
+func mergeIntervals(intervals []interval) []interval {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	sorted := make([]interval, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	merged := []interval{sorted[0]}
+
+	for _, iv := range sorted[1:] {
+		last := &merged[len(merged)-1]
+
+		if !iv.start.After(last.end) {
+			if iv.end.After(last.end) {
+				last.end = iv.end
+			}
+
+			continue
+		}
+
+		merged = append(merged, iv)
+	}
+
+	return merged
+}
+
+// totalHours returns the combined length of intervals, in hours.
+func totalHours(intervals []interval) float64 {
+	var total float64
+
+	for _, iv := range intervals {
+		total += iv.end.Sub(iv.start).Hours()
+	}
+
+	return total
+}
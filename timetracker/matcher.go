@@ -0,0 +1,62 @@
+package timetracker
+
+import (
+	"regexp"
+	"strings"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// Matcher decides whether an event in a given calendar should be
+// counted towards the tracked time. A TimeTracker matches an event if
+// any of its configured Matchers matches it.
+type Matcher interface {
+	Matches(calendarID string, ev *calendar.Event) bool
+}
+
+// PrefixMatcher matches events whose summary starts with Prefix. This
+// is the matcher the tool originally hardcoded for "SolarWinds" events.
+type PrefixMatcher struct {
+	Prefix string
+}
+
+func (m PrefixMatcher) Matches(_ string, ev *calendar.Event) bool {
+	return strings.HasPrefix(ev.Summary, m.Prefix)
+}
+
+// RegexMatcher matches events whose summary matches Pattern.
+type RegexMatcher struct {
+	Pattern *regexp.Regexp
+}
+
+func (m RegexMatcher) Matches(_ string, ev *calendar.Event) bool {
+	return m.Pattern.MatchString(ev.Summary)
+}
+
+// CalendarIDMatcher matches events purely by which calendar they came
+// from. If Include is non-empty, only those calendar IDs match; any ID
+// in Exclude never matches, even if also present in Include.
+type CalendarIDMatcher struct {
+	Include []string
+	Exclude []string
+}
+
+func (m CalendarIDMatcher) Matches(calendarID string, _ *calendar.Event) bool {
+	for _, id := range m.Exclude {
+		if id == calendarID {
+			return false
+		}
+	}
+
+	if len(m.Include) == 0 {
+		return true
+	}
+
+	for _, id := range m.Include {
+		if id == calendarID {
+			return true
+		}
+	}
+
+	return false
+}
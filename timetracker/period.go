@@ -0,0 +1,52 @@
+package timetracker
+
+import "time"
+
+const dayKeyLayout = "2006-01-02"
+
+func dayKey(t time.Time) string {
+	return t.Format(dayKeyLayout)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekBounds returns the start (00:00:00 on weekStart) and end
+// (23:59:59 six days later) of the week containing now.
+func weekBounds(now time.Time, weekStart time.Weekday) (time.Time, time.Time) {
+	day := startOfDay(now)
+
+	offset := int(day.Weekday() - weekStart)
+	if offset < 0 {
+		offset += 7
+	}
+
+	start := day.AddDate(0, 0, -offset)
+	end := time.Date(start.Year(), start.Month(), start.Day()+6, 23, 59, 59, 0, start.Location())
+
+	return start, end
+}
+
+func monthBounds(now time.Time) (time.Time, time.Time) {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+
+	return start, end
+}
+
+func quarterBounds(now time.Time) (time.Time, time.Time) {
+	quarterStartMonth := time.Month(((int(now.Month())-1)/3)*3 + 1)
+
+	start := time.Date(now.Year(), quarterStartMonth, 1, 0, 0, 0, 0, now.Location())
+	end := time.Date(start.Year(), start.Month()+3, 0, 23, 59, 59, 0, now.Location())
+
+	return start, end
+}
+
+func yearBounds(now time.Time) (time.Time, time.Time) {
+	start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	end := time.Date(now.Year(), time.December, 31, 23, 59, 59, 0, now.Location())
+
+	return start, end
+}
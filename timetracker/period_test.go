@@ -0,0 +1,80 @@
+package timetracker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekBounds(t *testing.T) {
+	// Wednesday, July 22, 2026.
+	now := time.Date(2026, time.July, 22, 15, 0, 0, 0, time.UTC)
+
+	start, end := weekBounds(now, time.Monday)
+
+	wantStart := time.Date(2026, time.July, 20, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, time.July, 26, 23, 59, 59, 0, time.UTC)
+
+	if !start.Equal(wantStart) {
+		t.Errorf("start = %v, want %v", start, wantStart)
+	}
+
+	if !end.Equal(wantEnd) {
+		t.Errorf("end = %v, want %v", end, wantEnd)
+	}
+}
+
+func TestWeekBoundsSundayStart(t *testing.T) {
+	now := time.Date(2026, time.July, 22, 15, 0, 0, 0, time.UTC)
+
+	start, end := weekBounds(now, time.Sunday)
+
+	wantStart := time.Date(2026, time.July, 19, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, time.July, 25, 23, 59, 59, 0, time.UTC)
+
+	if !start.Equal(wantStart) {
+		t.Errorf("start = %v, want %v", start, wantStart)
+	}
+
+	if !end.Equal(wantEnd) {
+		t.Errorf("end = %v, want %v", end, wantEnd)
+	}
+}
+
+func TestMonthBounds(t *testing.T) {
+	now := time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC)
+
+	start, end := monthBounds(now)
+
+	wantStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, time.February, 28, 23, 59, 59, 0, time.UTC)
+
+	if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+		t.Errorf("got [%v, %v], want [%v, %v]", start, end, wantStart, wantEnd)
+	}
+}
+
+func TestQuarterBounds(t *testing.T) {
+	now := time.Date(2026, time.August, 5, 0, 0, 0, 0, time.UTC)
+
+	start, end := quarterBounds(now)
+
+	wantStart := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, time.September, 30, 23, 59, 59, 0, time.UTC)
+
+	if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+		t.Errorf("got [%v, %v], want [%v, %v]", start, end, wantStart, wantEnd)
+	}
+}
+
+func TestYearBounds(t *testing.T) {
+	now := time.Date(2026, time.August, 5, 0, 0, 0, 0, time.UTC)
+
+	start, end := yearBounds(now)
+
+	wantStart := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+	if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+		t.Errorf("got [%v, %v], want [%v, %v]", start, end, wantStart, wantEnd)
+	}
+}
@@ -0,0 +1,40 @@
+package timetracker
+
+import "time"
+
+// Period summarizes tracked time over a date range.
+type Period struct {
+	Start time.Time
+	End   time.Time
+
+	// Total is the sum of matched event durations within the period,
+	// in hours.
+	Total float64
+	// Target is the sum of the configured per-weekday target hours for
+	// each calendar day in the period up to and including the reference
+	// time passed to Summarize; days that haven't happened yet aren't
+	// owed time.
+	Target float64
+	// Delta is Total - Target; positive means ahead of target.
+	Delta float64
+}
+
+// DayBreakdown is the tracked total for a single calendar day.
+type DayBreakdown struct {
+	Date  time.Time
+	Total float64
+}
+
+// Report is the result of TimeTracker.Summarize: totals for the week,
+// month, quarter and year containing the reference time, plus a
+// per-day breakdown of the week.
+type Report struct {
+	Week    Period
+	Month   Period
+	Quarter Period
+	Year    Period
+
+	// Days is the per-day breakdown of Week, one entry per calendar
+	// day from Week.Start to Week.End.
+	Days []DayBreakdown
+}
@@ -0,0 +1,196 @@
+// Package timetracker summarizes time spent in matched calendar events
+// against configurable per-weekday targets. It replaces the original
+// tool's hardcoded "SolarWinds" prefix and fixed 8h/day target with a
+// pluggable Matcher list and a weekday-keyed target, so the same code
+// works for anyone's calendar, not just one company's meetings.
+package timetracker
+
+import (
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// Event pairs a calendar.Event with the ID of the calendar it came
+// from, since calendar.Event itself doesn't carry its parent calendar.
+type Event struct {
+	CalendarID string
+	Event      *calendar.Event
+}
+
+// TimeTracker summarizes a set of events against a Config.
+type TimeTracker struct {
+	cfg Config
+}
+
+// New returns a TimeTracker configured by cfg.
+func New(cfg Config) *TimeTracker {
+	return &TimeTracker{cfg: cfg}
+}
+
+// Summarize computes a Report for the week, month, quarter and year
+// containing now, counting only events that match the tracker's
+// Matchers. Multi-day events are clipped to the calendar days they
+// touch, and overlapping matched events are merged so double-booked
+// time is only counted once.
+func (t *TimeTracker) Summarize(events []Event, now time.Time) Report {
+	dayIntervals := map[string][]interval{}
+	dayTotals := map[string]float64{}
+
+	for _, ev := range events {
+		if ev.Event.Status == "cancelled" {
+			continue
+		}
+
+		if !t.matches(ev) {
+			continue
+		}
+
+		t.addContribution(ev.Event, dayIntervals, dayTotals)
+	}
+
+	for day, intervals := range dayIntervals {
+		dayTotals[day] += totalHours(mergeIntervals(intervals))
+	}
+
+	weekStart, weekEnd := weekBounds(now, t.cfg.WeekStart)
+	monthStart, monthEnd := monthBounds(now)
+	quarterStart, quarterEnd := quarterBounds(now)
+	yearStart, yearEnd := yearBounds(now)
+
+	return Report{
+		Week:    t.period(dayTotals, weekStart, weekEnd, now),
+		Month:   t.period(dayTotals, monthStart, monthEnd, now),
+		Quarter: t.period(dayTotals, quarterStart, quarterEnd, now),
+		Year:    t.period(dayTotals, yearStart, yearEnd, now),
+		Days:    t.dayBreakdown(dayTotals, weekStart, weekEnd),
+	}
+}
+
+func (t *TimeTracker) matches(ev Event) bool {
+	if len(t.cfg.Matchers) == 0 {
+		return true
+	}
+
+	for _, m := range t.cfg.Matchers {
+		if m.Matches(ev.CalendarID, ev.Event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addContribution splits ev's span across the calendar days it
+// touches. Timed events add a clock-time interval per day they
+// overlap, to be merged later against other events on the same day.
+// All-day events (Start.Date/End.Date, no DateTime) instead add a flat
+// AllDayEventHours bucket per day they span, since they have no real
+// clock time to merge against.
+func (t *TimeTracker) addContribution(ev *calendar.Event, dayIntervals map[string][]interval, dayTotals map[string]float64) {
+	if ev.Start == nil || ev.End == nil {
+		return
+	}
+
+	if ev.Start.DateTime != "" && ev.End.DateTime != "" {
+		startTime, err := time.Parse(time.RFC3339, ev.Start.DateTime)
+		if err != nil {
+			return
+		}
+
+		endTime, err := time.Parse(time.RFC3339, ev.End.DateTime)
+		if err != nil {
+			return
+		}
+
+		for day := startOfDay(startTime); day.Before(endTime); day = day.AddDate(0, 0, 1) {
+			dayStart := maxTime(day, startTime)
+			dayEnd := minTime(day.AddDate(0, 0, 1), endTime)
+
+			if !dayStart.Before(dayEnd) {
+				continue
+			}
+
+			key := dayKey(day)
+			dayIntervals[key] = append(dayIntervals[key], interval{start: dayStart, end: dayEnd})
+		}
+
+		return
+	}
+
+	if ev.Start.Date != "" && ev.End.Date != "" {
+		startDate, err := time.ParseInLocation(dayKeyLayout, ev.Start.Date, time.Local)
+		if err != nil {
+			return
+		}
+
+		endDate, err := time.ParseInLocation(dayKeyLayout, ev.End.Date, time.Local)
+		if err != nil {
+			return
+		}
+
+		// End.Date is exclusive, per the Calendar API.
+		for day := startDate; day.Before(endDate); day = day.AddDate(0, 0, 1) {
+			dayTotals[dayKey(day)] += t.cfg.AllDayEventHours
+		}
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+func (t *TimeTracker) targetHours(weekday time.Weekday) float64 {
+	return t.cfg.TargetHours[weekday]
+}
+
+// period totals dayTotals over [start, end], and accrues Target only
+// through min(end, now): a day that hasn't happened yet isn't owed
+// time, so an in-progress quarter or year doesn't report a large
+// negative Delta purely because its later days haven't occurred.
+func (t *TimeTracker) period(dayTotals map[string]float64, start, end, now time.Time) Period {
+	var total, target float64
+
+	targetEnd := end
+	if today := startOfDay(now); today.Before(targetEnd) {
+		targetEnd = today
+	}
+
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		total += dayTotals[dayKey(d)]
+
+		if !d.After(targetEnd) {
+			target += t.targetHours(d.Weekday())
+		}
+	}
+
+	return Period{
+		Start:  start,
+		End:    end,
+		Total:  total,
+		Target: target,
+		Delta:  total - target,
+	}
+}
+
+func (t *TimeTracker) dayBreakdown(dayTotals map[string]float64, start, end time.Time) []DayBreakdown {
+	var days []DayBreakdown
+
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, DayBreakdown{Date: d, Total: dayTotals[dayKey(d)]})
+	}
+
+	return days
+}
@@ -0,0 +1,116 @@
+package timetracker
+
+import (
+	"testing"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func timedEvent(summary, start, end string) Event {
+	return Event{
+		CalendarID: "cal1",
+		Event: &calendar.Event{
+			Summary: summary,
+			Start:   &calendar.EventDateTime{DateTime: start},
+			End:     &calendar.EventDateTime{DateTime: end},
+		},
+	}
+}
+
+func allDayEvent(summary, startDate, endDate string) Event {
+	return Event{
+		CalendarID: "cal1",
+		Event: &calendar.Event{
+			Summary: summary,
+			Start:   &calendar.EventDateTime{Date: startDate},
+			End:     &calendar.EventDateTime{Date: endDate},
+		},
+	}
+}
+
+func testTracker() *TimeTracker {
+	return New(Config{
+		Matchers:         []Matcher{PrefixMatcher{Prefix: "Test"}},
+		TargetHours:      DefaultTargetHours(),
+		WeekStart:        time.Monday,
+		AllDayEventHours: 8,
+	})
+}
+
+func TestSummarizeMergesOverlappingEvents(t *testing.T) {
+	events := []Event{
+		timedEvent("Test A", "2026-07-22T09:00:00Z", "2026-07-22T10:00:00Z"),
+		timedEvent("Test B", "2026-07-22T09:30:00Z", "2026-07-22T10:30:00Z"),
+	}
+
+	now := time.Date(2026, time.July, 22, 12, 0, 0, 0, time.UTC)
+	report := testTracker().Summarize(events, now)
+
+	// 09:00-10:30 merged, not the 2h the two events would sum to
+	// unmerged.
+	const want = 1.5
+	if report.Week.Total != want {
+		t.Errorf("Week.Total = %v, want %v", report.Week.Total, want)
+	}
+}
+
+func TestSummarizeClipsMultiDayEventToWindow(t *testing.T) {
+	// Week is Mon 2026-07-20 through Sun 2026-07-26. The event runs
+	// from Friday night into the following Monday, so only the
+	// Fri/Sat/Sun portion inside this week should count.
+	events := []Event{
+		timedEvent("Test spanning", "2026-07-24T22:00:00Z", "2026-07-27T02:00:00Z"),
+	}
+
+	now := time.Date(2026, time.July, 22, 12, 0, 0, 0, time.UTC)
+	report := testTracker().Summarize(events, now)
+
+	const want = 50.0 // 2h Fri + 24h Sat + 24h Sun
+	if report.Week.Total != want {
+		t.Errorf("Week.Total = %v, want %v", report.Week.Total, want)
+	}
+}
+
+func TestSummarizeSplitsMultiDayAllDayEvent(t *testing.T) {
+	// End.Date is exclusive, so this covers Jul 24-26.
+	events := []Event{
+		allDayEvent("Test offsite", "2026-07-24", "2026-07-27"),
+	}
+
+	now := time.Date(2026, time.July, 22, 12, 0, 0, 0, time.UTC)
+	report := testTracker().Summarize(events, now)
+
+	const want = 24.0 // 8h/day across 3 days
+	if report.Week.Total != want {
+		t.Errorf("Week.Total = %v, want %v", report.Week.Total, want)
+	}
+}
+
+func TestSummarizeTargetDoesNotIncludeDaysAfterNow(t *testing.T) {
+	// Mid-year, so Year's window (Jan 1-Dec 31) extends well past now.
+	now := time.Date(2026, time.July, 22, 12, 0, 0, 0, time.UTC)
+	report := testTracker().Summarize(nil, now)
+
+	yearStart := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	wantTarget := 0.0
+	for d := yearStart; !d.After(now); d = d.AddDate(0, 0, 1) {
+		wantTarget += DefaultTargetHours()[d.Weekday()]
+	}
+
+	if report.Year.Target != wantTarget {
+		t.Errorf("Year.Target = %v, want %v (target through today only, not through Dec 31)", report.Year.Target, wantTarget)
+	}
+}
+
+func TestSummarizeSkipsCancelledEvents(t *testing.T) {
+	ev := timedEvent("Test cancelled", "2026-07-22T09:00:00Z", "2026-07-22T10:00:00Z")
+	ev.Event.Status = "cancelled"
+
+	now := time.Date(2026, time.July, 22, 12, 0, 0, 0, time.UTC)
+	report := testTracker().Summarize([]Event{ev}, now)
+
+	if report.Week.Total != 0 {
+		t.Errorf("Week.Total = %v, want 0", report.Week.Total)
+	}
+}